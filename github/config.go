@@ -0,0 +1,58 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/Flaconi/terraform-provider-github/internal/teamcache"
+	"github.com/google/go-github/v41/github"
+	"github.com/shurcooL/githubv4"
+)
+
+// teamCacheTTL is how long Owner.TeamCache serves team reads out of its
+// last GraphQL sweep before the next resourceGithubTeamRead call triggers
+// a fresh one. 30s comfortably covers a single "terraform apply" window
+// without masking changes made outside of Terraform for long.
+const teamCacheTTL = 30 * time.Second
+
+// Owner is the meta value every resource/data source in this package
+// receives as `meta interface{}`. It carries the configured API clients
+// plus the provider-level tuning knobs that would otherwise have to be
+// threaded through every CRUD function by hand.
+type Owner struct {
+	v3client *github.Client
+	v4client *githubv4.Client
+	name     string
+	id       int64
+
+	StopContext context.Context
+
+	// RetryMaxAttempts, RetryMaxWait and RetryOnStatusCodes back
+	// teamRetryConfig and tune how resourceGithubTeam's retry.Do calls
+	// behave; they come from the provider's retry_max_attempts,
+	// retry_max_wait_seconds and retry_on_status_codes arguments.
+	RetryMaxAttempts   int
+	RetryMaxWait       time.Duration
+	RetryOnStatusCodes []int
+
+	// TeamCache backs resourceGithubTeamRead's GraphQL-based fast path,
+	// populated here so every resource sharing this Owner sees the same
+	// cache instead of each sweeping the organization on its own.
+	TeamCache *teamcache.Cache
+}
+
+// newOwner builds the Owner meta value from a configured v3/v4 client
+// pair, the provider's retry tuning, and the organization name/ID.
+func newOwner(v3client *github.Client, v4client *githubv4.Client, orgName string, orgID int64, retryMaxAttempts, retryMaxWaitSeconds int, retryOnStatusCodes []int) *Owner {
+	return &Owner{
+		v3client:           v3client,
+		v4client:           v4client,
+		name:               orgName,
+		id:                 orgID,
+		StopContext:        context.Background(),
+		RetryMaxAttempts:   retryMaxAttempts,
+		RetryMaxWait:       time.Duration(retryMaxWaitSeconds) * time.Second,
+		RetryOnStatusCodes: retryOnStatusCodes,
+		TeamCache:          teamcache.NewCache(teamCacheTTL),
+	}
+}
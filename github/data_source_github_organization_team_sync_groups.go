@@ -0,0 +1,64 @@
+package github
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// dataSourceGithubOrganizationTeamSyncGroups lists the SAML IdP groups
+// available to the organization, so users can look up the group IDs
+// `github_team_sync_group_mapping` and `github_team`'s `idp_groups` block
+// expect.
+func dataSourceGithubOrganizationTeamSyncGroups() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGithubOrganizationTeamSyncGroupsRead,
+
+		Schema: map[string]*schema.Schema{
+			"groups": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"group_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"group_description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGithubOrganizationTeamSyncGroupsRead(d *schema.ResourceData, meta interface{}) error {
+	err := checkOrganization(meta)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(*Owner).v3client
+	orgName := meta.(*Owner).name
+	ctx := context.Background()
+
+	log.Printf("[DEBUG] Reading team sync IdP groups for organization: %s", orgName)
+	idpGroups, _, err := client.Teams.ListIDPGroupsInOrganization(ctx, orgName, nil)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(orgName)
+	d.Set("groups", flattenTeamSyncGroups(idpGroups.Groups))
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package github
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+)
+
+// Provider returns the github provider's *schema.Provider, wiring the
+// provider-level arguments into the Owner meta value every resource and
+// data source receives.
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GITHUB_TOKEN", nil),
+				Description: "The OAuth token used to authenticate with the GitHub API.",
+			},
+			"organization": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GITHUB_ORGANIZATION", nil),
+				Description: "The GitHub organization name to manage.",
+			},
+			"base_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GITHUB_BASE_URL", "https://api.github.com/"),
+				Description: "The GitHub Enterprise base API URL.",
+			},
+			"retry_max_attempts": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Maximum number of attempts (including the first) the provider's team retry/backoff subsystem makes before giving up. Defaults to the internal/retry package's own default (10) when left at 0.",
+			},
+			"retry_max_wait_seconds": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Upper bound, in seconds, on the total time the team retry/backoff subsystem spends retrying a single operation. Defaults to the internal/retry package's own default (5 minutes) when left at 0.",
+			},
+			"retry_on_status_codes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Additional HTTP status codes (beyond 5xx, which is always retried) the team retry/backoff subsystem treats as transient. Defaults to [404], to tolerate teams that haven't propagated yet under parallel apply.",
+			},
+		},
+
+		ResourcesMap: map[string]*schema.Resource{
+			"github_team":                    resourceGithubTeam(),
+			"github_team_sync_group_mapping": resourceGithubTeamSyncGroupMapping(),
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"github_organization_team_sync_groups": dataSourceGithubOrganizationTeamSyncGroups(),
+		},
+
+		ConfigureFunc: providerConfigure,
+	}
+}
+
+// publicGithubBaseURL is base_url's default - providerConfigure only builds
+// an enterprise client when the provider block points somewhere else.
+const publicGithubBaseURL = "https://api.github.com/"
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	token := d.Get("token").(string)
+	orgName := d.Get("organization").(string)
+	baseURL := d.Get("base_url").(string)
+
+	httpClient := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: token},
+	))
+
+	v3client := github.NewClient(httpClient)
+	if baseURL != "" && baseURL != publicGithubBaseURL {
+		enterpriseClient, err := github.NewEnterpriseClient(baseURL, baseURL, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		v3client = enterpriseClient
+	}
+	v4client := githubv4.NewClient(httpClient)
+
+	var orgID int64
+	if orgName != "" {
+		org, _, err := v3client.Organizations.Get(context.Background(), orgName)
+		if err != nil {
+			return nil, err
+		}
+		orgID = org.GetID()
+	}
+
+	retryOnStatusCodes := make([]int, 0)
+	for _, code := range d.Get("retry_on_status_codes").([]interface{}) {
+		retryOnStatusCodes = append(retryOnStatusCodes, code.(int))
+	}
+	if len(retryOnStatusCodes) == 0 {
+		retryOnStatusCodes = []int{http.StatusNotFound}
+	}
+
+	owner := newOwner(
+		v3client,
+		v4client,
+		orgName,
+		orgID,
+		d.Get("retry_max_attempts").(int),
+		d.Get("retry_max_wait_seconds").(int),
+		retryOnStatusCodes,
+	)
+
+	return owner, nil
+}
@@ -0,0 +1,53 @@
+package github
+
+import (
+	"context"
+
+	"github.com/Flaconi/terraform-provider-github/internal/framework"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// NewProviderServerFactory returns the tfprotov5.ProviderServer constructor
+// the binary's main.go passes to plugin.Serve.
+//
+// useLegacyTeamResource can't be a provider block argument: tf5muxserver
+// merges the SDKv2 and framework servers' schemas once, at server-
+// construction time, before any provider block is ever evaluated, so which
+// server owns github_team can't be decided from inside Configure. main.go
+// resolves this flag the same way it resolves everything else that has to
+// be known before the provider block is configured: an environment
+// variable, GITHUB_USE_LEGACY_TEAM_RESOURCE, defaulting to true.
+//
+// When useLegacyTeamResource is true, github_team keeps being served by
+// the SDKv2 resourceGithubTeam and no muxing happens at all. When false,
+// github_team is removed from the SDKv2 provider's ResourcesMap and served
+// instead by internal/framework's TeamResource, muxed in via tf5muxserver.
+func NewProviderServerFactory(useLegacyTeamResource bool) func() tfprotov5.ProviderServer {
+	sdkProvider := Provider()
+
+	if useLegacyTeamResource {
+		return sdkProvider.GRPCProvider
+	}
+
+	delete(sdkProvider.ResourcesMap, "github_team")
+	frameworkServerFactory := providerserver.NewProtocol5(framework.New())
+
+	return func() tfprotov5.ProviderServer {
+		ctx := context.Background()
+
+		muxServer, err := tf5muxserver.NewMuxServer(ctx,
+			sdkProvider.GRPCProvider,
+			frameworkServerFactory,
+		)
+		if err != nil {
+			// NewMuxServer only fails on a schema/resource-type collision
+			// between the two servers, which would be a provider bug, not
+			// a runtime condition - fail fast the same way plugin.Serve's
+			// other setup failures do.
+			panic(err)
+		}
+		return muxServer.ProviderServer()
+	}
+}
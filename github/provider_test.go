@@ -0,0 +1,37 @@
+package github
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+var testAccProviders map[string]terraform.ResourceProvider
+var testAccProvider *schema.Provider
+
+func init() {
+	testAccProvider = Provider()
+	testAccProviders = map[string]terraform.ResourceProvider{
+		"github": testAccProvider,
+	}
+}
+
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+// testAccPreCheck fails acceptance tests unless both GITHUB_TOKEN and
+// GITHUB_ORGANIZATION are set, the same two environment variables
+// providerConfigure falls back to.
+func testAccPreCheck(t *testing.T) {
+	if os.Getenv("GITHUB_TOKEN") == "" {
+		t.Fatal("GITHUB_TOKEN must be set for acceptance tests")
+	}
+	if os.Getenv("GITHUB_ORGANIZATION") == "" {
+		t.Fatal("GITHUB_ORGANIZATION must be set for acceptance tests")
+	}
+}
@@ -5,24 +5,33 @@ import (
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
+	"github.com/Flaconi/terraform-provider-github/internal/retry"
+	"github.com/Flaconi/terraform-provider-github/internal/teamcache"
 	"github.com/google/go-github/v41/github"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/shurcooL/githubv4"
 )
 
-/*
-These constants are used to retry API on various operations.
-This is required because Terraform apply/destroy runs in parallel and when
-looping through a module or resource a team name could have been changed by another thread,
-a parent team could have been removed or various other parallel issues.
-To mitigate this, we're simply retrying the API to double check its actual state.
-See their corresponding for loops for further description.
-*/
-const github_team_api_retry = 10
-const github_team_api_wait = 5
+// teamRetryConfig builds a retry.Config from the provider's configured
+// tuning knobs (retry_max_attempts / retry_max_wait_seconds /
+// retry_on_status_codes), falling back to retry.DefaultConfig when the
+// provider block leaves them unset. This replaces the old hard-coded
+// github_team_api_retry/github_team_api_wait constants: Terraform
+// apply/destroy runs in parallel, and when looping through a module or
+// resource a team name could have been changed by another thread, a
+// parent team could have been removed, or GitHub could start throttling
+// the run, so we need to distinguish those cases rather than blindly
+// sleeping and retrying everything.
+func teamRetryConfig(meta interface{}) retry.Config {
+	owner := meta.(*Owner)
+	return retry.Config{
+		MaxAttempts: owner.RetryMaxAttempts,
+		MaxWait:     owner.RetryMaxWait,
+		StatusCodes: owner.RetryOnStatusCodes,
+	}.WithDefaults()
+}
 
 func resourceGithubTeam() *schema.Resource {
 	return &schema.Resource{
@@ -85,10 +94,73 @@ func resourceGithubTeam() *schema.Resource {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
+			"manage_repositories": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to manage this team's repository access via the `permissions` block below, instead of separate `github_team_repository` resources.",
+			},
+			"permission": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultTeamRepositoryPermission,
+				ValidateFunc: validateValueFunc(teamRepositoryPermissions),
+				Description:  "Default permission granted to every repository listed in `permissions` that doesn't set its own `permission`. Only used when `manage_repositories` is `true`.",
+			},
+			"idp_groups": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "SAML IdP groups synchronized with this team on GitHub Enterprise Cloud. See `github_organization_team_sync_groups` to look up IDs. Mutually exclusive in practice with `ldap_dn`, which is the GHES equivalent.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group_id": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"group_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"group_description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"permissions": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Repositories this team has access to. Only applied when `manage_repositories` is `true`.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"repository": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Slug of the repository to grant the team access to.",
+						},
+						"permission": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateValueFunc(teamRepositoryPermissions),
+							Description:  "Permission to grant on this repository. Defaults to the resource's top-level `permission`.",
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
+// defaultTeamRepositoryPermission is used for entries in "permissions" that
+// don't set their own "permission" and for the resource's own "permission"
+// default.
+const defaultTeamRepositoryPermission = "pull"
+
+// teamRepositoryPermissions are the permission levels GitHub accepts for
+// team-to-repository grants.
+var teamRepositoryPermissions = []string{"pull", "triage", "push", "maintain", "admin"}
+
 func resourceGithubTeamCreate(d *schema.ResourceData, meta interface{}) error {
 	err := checkOrganization(meta)
 	if err != nil {
@@ -106,32 +178,25 @@ func resourceGithubTeamCreate(d *schema.ResourceData, meta interface{}) error {
 		Privacy:     github.String(d.Get("privacy").(string)),
 	}
 
+	ctx := context.Background()
+
 	if parentTeamIdString, ok := d.GetOk("parent_team_id"); ok {
-		/*
-			When creating nested teams via Terraform by looping through a module or resource
-			the parent team might not have been created yet (in "terraform apply" parallel runs),
-			so we are giving it some time to create the parent team and will repeatedly check
-			if the parent exists (has been created by another parallel run).
-		*/
-		teamId, err := getTeamID(parentTeamIdString.(string), meta)
-		for i := 0; i < github_team_api_retry; i++ {
-			// Try again on error
-			if err != nil {
-				log.Printf("[WARN] Fetching parent team: Retry (%d/%d)", i, github_team_api_retry)
-				time.Sleep(github_team_api_wait * time.Second)
-				teamId, err = getTeamID(parentTeamIdString.(string), meta)
-				continue
-			}
-			// Exit loop on success
-			break
-		}
+		// When creating nested teams via Terraform by looping through a
+		// module or resource the parent team might not have been created
+		// yet (in "terraform apply" parallel runs), so we retry until it
+		// shows up (or another parallel run's team really doesn't exist).
+		var teamId int64
+		err = retry.Do(ctx, teamRetryConfig(meta), func() error {
+			var rErr error
+			teamId, rErr = getTeamID(parentTeamIdString.(string), meta)
+			return rErr
+		})
 		if err != nil {
 			log.Printf("[ERROR] Unable to find parent team")
 			return err
 		}
 		newTeam.ParentTeamID = &teamId
 	}
-	ctx := context.Background()
 
 	log.Printf("[DEBUG] Creating team: %s (%s)", name, ownerName)
 	githubTeam, _, err := client.Teams.CreateTeam(ctx,
@@ -158,7 +223,25 @@ func resourceGithubTeamCreate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if groups := d.Get("idp_groups").(*schema.Set).List(); len(groups) > 0 {
+		mapping := github.IDPGroupList{Groups: expandTeamSyncGroups(groups)}
+		err = retry.Do(ctx, teamRetryConfig(meta), func() error {
+			_, _, rErr := client.Teams.CreateOrUpdateIDPGroupConnectionsBySlug(ctx, ownerName, *githubTeam.Slug, mapping)
+			return rErr
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	d.SetId(strconv.FormatInt(githubTeam.GetID(), 10))
+
+	if d.Get("manage_repositories").(bool) {
+		if err := manageTeamRepositories(d, meta, *githubTeam.Slug); err != nil {
+			return err
+		}
+	}
+
 	return resourceGithubTeamRead(d, meta)
 }
 
@@ -180,40 +263,37 @@ func resourceGithubTeamRead(d *schema.ResourceData, meta interface{}) error {
 		ctx = context.WithValue(ctx, ctxEtag, d.Get("etag").(string))
 	}
 
-	/*
-		Slug-name specific (as opposed to using team ID):
-		When using slug-name to read GitHub teams it could be that another parallel thread of TF
-		(when looping through a module or resource) still needs to apply changes (rename the team name)
-		and thus it could be that we don't find it right away.
-		In order to mitigate this, we will loop this call and give the API a sane waiting time, hoping
-		the other thread has finished renaming the team in the mean time.
-	*/
-	log.Printf("[DEBUG] Reading team: %s", d.Id())
-	team, resp, err := client.Teams.GetTeamByID(ctx, orgId, id)
-	for i := 0; i < github_team_api_retry; i++ {
-		if err != nil {
-			if ghErr, ok := err.(*github.ErrorResponse); ok {
-				if ghErr.Response.StatusCode == http.StatusNotModified {
-					return nil
-				}
-				// When using slug-name instead of ID, the new team name might not have been changed
-				// so we need to include this in the loop.
-				if ghErr.Response.StatusCode == http.StatusNotFound {
-					log.Printf("[WARN] Looking up team: Retry on 404 (%d/%d)", i, github_team_api_retry)
-					time.Sleep(github_team_api_wait * time.Second)
-					team, resp, err = client.Teams.GetTeamByID(ctx, orgId, id)
-					continue
-				}
-				log.Printf("[WARN] Looking up team: Retry on error (%d/%d)", i, github_team_api_retry)
-				time.Sleep(github_team_api_wait * time.Second)
-				team, resp, err = client.Teams.GetTeamByID(ctx, orgId, id)
-				continue
-			}
-			return err
+	// Large orgs pay the retry.Do backoff penalty once per team read, which
+	// adds up under parallel apply. Before falling back to REST, consult
+	// the GraphQL-backed team cache: it sweeps the whole organization's
+	// teams in O(teams/100) requests and serves reads out of that for its
+	// TTL, so most resourceGithubTeamRead calls in an apply never hit the
+	// REST/retry path at all. ldap_dn, members_count and etag aren't part
+	// of the GraphQL sweep, so a cache hit leaves those fields as-is.
+	if owner := meta.(*Owner); owner.TeamCache != nil {
+		if refreshErr := owner.TeamCache.Refresh(ctx, owner.v4client, owner.name); refreshErr != nil {
+			log.Printf("[WARN] Refreshing team cache: %s", refreshErr)
+		} else if cached, ok := owner.TeamCache.GetByID(id); ok {
+			log.Printf("[DEBUG] Serving team %s from team cache", d.Id())
+			return resourceGithubTeamReadFromCache(d, meta, cached)
 		}
-		// Exit loop on success
-		break
 	}
+
+	// Slug-name specific (as opposed to using team ID): when using
+	// slug-name to read GitHub teams it could be that another parallel
+	// thread of TF (when looping through a module or resource) still
+	// needs to apply changes (rename the team name) and thus it could be
+	// that we don't find it right away. retry.Do gives the API a sane
+	// waiting time, with backoff and rate-limit awareness, hoping the
+	// other thread has finished renaming the team in the mean time.
+	log.Printf("[DEBUG] Reading team: %s", d.Id())
+	var team *github.Team
+	var resp *github.Response
+	err = retry.Do(ctx, teamRetryConfig(meta), func() error {
+		var rErr error
+		team, resp, rErr = client.Teams.GetTeamByID(ctx, orgId, id)
+		return rErr
+	})
 	if err != nil {
 		if ghErr, ok := err.(*github.ErrorResponse); ok {
 			if ghErr.Response.StatusCode == http.StatusNotModified {
@@ -243,6 +323,25 @@ func resourceGithubTeamRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("node_id", team.GetNodeID())
 	d.Set("members_count", team.GetMembersCount())
 
+	if d.Get("manage_repositories").(bool) {
+		if err := readTeamRepositories(d, meta, team.GetSlug()); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("idp_groups").(*schema.Set).Len() > 0 {
+		var idpGroups *github.IDPGroupList
+		err = retry.Do(ctx, teamRetryConfig(meta), func() error {
+			var rErr error
+			idpGroups, _, rErr = client.Teams.ListIDPGroupsForTeamBySlug(ctx, meta.(*Owner).name, team.GetSlug())
+			return rErr
+		})
+		if err != nil {
+			return err
+		}
+		d.Set("idp_groups", flattenTeamSyncGroups(idpGroups.Groups))
+	}
+
 	return nil
 }
 
@@ -261,27 +360,20 @@ func resourceGithubTeamUpdate(d *schema.ResourceData, meta interface{}) error {
 		Privacy:     github.String(d.Get("privacy").(string)),
 	}
 
+	ctx := context.WithValue(context.Background(), ctxId, d.Id())
+
 	if parentTeamIdString, ok := d.GetOk("parent_team_id"); ok {
-		/*
-			Slug-name specific (as opposed to using team ID):
-			When updating nested teams via Terraform by looping through a module or resource
-			the parent team might not have been updated by a new slug-name yet
-			(in "terraform apply" parallel runs), so we are giving it some time to create the parent
-			team and will repeatedly check if the parent exists
-			(has been created by another parallel run).
-		*/
-		teamId, err := getTeamID(parentTeamIdString.(string), meta)
-		for i := 0; i < github_team_api_retry; i++ {
-			// Try again on error
-			if err != nil {
-				log.Printf("[WARN] Fetching parent team: Retry (%d/%d)", i, github_team_api_retry)
-				time.Sleep(github_team_api_wait * time.Second)
-				teamId, err = getTeamID(parentTeamIdString.(string), meta)
-				continue
-			}
-			// Exit loop on success
-			break
-		}
+		// Slug-name specific (as opposed to using team ID): when updating
+		// nested teams via Terraform by looping through a module or
+		// resource the parent team might not have been updated to its new
+		// slug-name yet (in "terraform apply" parallel runs), so we retry
+		// until the parent team resolves.
+		var teamId int64
+		err = retry.Do(ctx, teamRetryConfig(meta), func() error {
+			var rErr error
+			teamId, rErr = getTeamID(parentTeamIdString.(string), meta)
+			return rErr
+		})
 		if err != nil {
 			log.Printf("[ERROR] Unable to find parent team")
 			return err
@@ -293,7 +385,6 @@ func resourceGithubTeamUpdate(d *schema.ResourceData, meta interface{}) error {
 	if err != nil {
 		return unconvertibleIdErr(d.Id(), err)
 	}
-	ctx := context.WithValue(context.Background(), ctxId, d.Id())
 
 	log.Printf("[DEBUG] Updating team: %s", d.Id())
 	team, _, err := client.Teams.EditTeamByID(ctx, orgId, teamId, editedTeam, false)
@@ -312,7 +403,25 @@ func resourceGithubTeamUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if d.HasChange("idp_groups") {
+		mapping := github.IDPGroupList{Groups: expandTeamSyncGroups(d.Get("idp_groups").(*schema.Set).List())}
+		err = retry.Do(ctx, teamRetryConfig(meta), func() error {
+			_, _, rErr := client.Teams.CreateOrUpdateIDPGroupConnectionsBySlug(ctx, meta.(*Owner).name, team.GetSlug(), mapping)
+			return rErr
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	d.SetId(strconv.FormatInt(team.GetID(), 10))
+
+	if d.Get("manage_repositories").(bool) {
+		if err := manageTeamRepositories(d, meta, team.GetSlug()); err != nil {
+			return err
+		}
+	}
+
 	return resourceGithubTeamRead(d, meta)
 }
 
@@ -332,7 +441,10 @@ func resourceGithubTeamDelete(d *schema.ResourceData, meta interface{}) error {
 	ctx := context.WithValue(context.Background(), ctxId, d.Id())
 
 	log.Printf("[DEBUG] Deleting team: %s", d.Id())
-	_, err = client.Teams.DeleteTeamByID(ctx, orgId, id)
+	err = retry.Do(ctx, teamRetryConfig(meta), func() error {
+		_, rErr := client.Teams.DeleteTeamByID(ctx, orgId, id)
+		return rErr
+	})
 	/*
 		When deleting a team and it failed, we need to check if it has already been deleted meanwhile.
 		This could be the case when deleting nested teams via Terraform by looping through a module
@@ -399,3 +511,180 @@ func removeDefaultMaintainer(teamSlug string, meta interface{}) error {
 
 	return nil
 }
+
+// teamRepositoryGrant is the normalized form of one "permissions" set entry:
+// a repository slug and the permission level the team should hold on it.
+type teamRepositoryGrant struct {
+	repository string
+	permission string
+}
+
+// expandTeamRepositoryGrants reads the "permissions" set and the top-level
+// "permission" default into a map keyed by repository slug, so it can be
+// diffed against what GitHub currently reports.
+func expandTeamRepositoryGrants(d *schema.ResourceData) map[string]teamRepositoryGrant {
+	defaultPermission := d.Get("permission").(string)
+	grants := make(map[string]teamRepositoryGrant)
+	for _, raw := range d.Get("permissions").(*schema.Set).List() {
+		entry := raw.(map[string]interface{})
+		repository := entry["repository"].(string)
+		permission := entry["permission"].(string)
+		if permission == "" {
+			permission = defaultPermission
+		}
+		grants[repository] = teamRepositoryGrant{repository: repository, permission: permission}
+	}
+	return grants
+}
+
+// listTeamRepositoryGrants fetches the repositories GitHub currently
+// reports for the team, across all pages, as a map keyed by repository
+// slug so it can be diffed against the desired state.
+func listTeamRepositoryGrants(teamSlug string, meta interface{}) (map[string]teamRepositoryGrant, error) {
+	client := meta.(*Owner).v3client
+	orgName := meta.(*Owner).name
+	ctx := meta.(*Owner).StopContext
+
+	grants := make(map[string]teamRepositoryGrant)
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		var repos []*github.Repository
+		var resp *github.Response
+		err := retry.Do(ctx, teamRetryConfig(meta), func() error {
+			var rErr error
+			repos, resp, rErr = client.Teams.ListTeamReposBySlug(ctx, orgName, teamSlug, opts)
+			return rErr
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			grants[repo.GetName()] = teamRepositoryGrant{
+				repository: repo.GetName(),
+				permission: highestTeamRepositoryPermission(repo.GetPermissions()),
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return grants, nil
+}
+
+// highestTeamRepositoryPermission picks the permission level the GitHub API
+// considers the repository's permission map to represent, checking from most
+// to least privileged by walking teamRepositoryPermissions (which is ordered
+// least to most privileged) from the end.
+func highestTeamRepositoryPermission(permissions map[string]bool) string {
+	for i := len(teamRepositoryPermissions) - 1; i >= 0; i-- {
+		if permissions[teamRepositoryPermissions[i]] {
+			return teamRepositoryPermissions[i]
+		}
+	}
+	return defaultTeamRepositoryPermission
+}
+
+// manageTeamRepositories reconciles the team's repository access with the
+// desired "permissions" set by diffing against what GitHub currently
+// reports, then adding, updating, or removing grants as needed.
+func manageTeamRepositories(d *schema.ResourceData, meta interface{}, teamSlug string) error {
+	client := meta.(*Owner).v3client
+	orgName := meta.(*Owner).name
+	ctx := meta.(*Owner).StopContext
+
+	desired := expandTeamRepositoryGrants(d)
+	actual, err := listTeamRepositoryGrants(teamSlug, meta)
+	if err != nil {
+		return err
+	}
+
+	for repository, grant := range desired {
+		if existing, ok := actual[repository]; ok && existing.permission == grant.permission {
+			continue
+		}
+		log.Printf("[DEBUG] Granting team %s %q access to repository: %s", teamSlug, grant.permission, repository)
+		opts := &github.TeamAddTeamRepoOptions{Permission: grant.permission}
+		err := retry.Do(ctx, teamRetryConfig(meta), func() error {
+			_, rErr := client.Teams.AddTeamRepoBySlug(ctx, orgName, teamSlug, orgName, repository, opts)
+			return rErr
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for repository := range actual {
+		if _, ok := desired[repository]; ok {
+			continue
+		}
+		log.Printf("[DEBUG] Removing team %s access to repository: %s", teamSlug, repository)
+		err := retry.Do(ctx, teamRetryConfig(meta), func() error {
+			_, rErr := client.Teams.RemoveTeamRepoBySlug(ctx, orgName, teamSlug, orgName, repository)
+			return rErr
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readTeamRepositories refreshes the "permissions" set from what GitHub
+// currently reports for the team.
+func readTeamRepositories(d *schema.ResourceData, meta interface{}, teamSlug string) error {
+	grants, err := listTeamRepositoryGrants(teamSlug, meta)
+	if err != nil {
+		return err
+	}
+
+	permissions := make([]interface{}, 0, len(grants))
+	for _, grant := range grants {
+		permissions = append(permissions, map[string]interface{}{
+			"repository": grant.repository,
+			"permission": grant.permission,
+		})
+	}
+	return d.Set("permissions", permissions)
+}
+
+// resourceGithubTeamReadFromCache fills in the resource data from a
+// teamcache hit instead of a REST GetTeamByID call, still reconciling
+// manage_repositories/idp_groups since those aren't part of the GraphQL
+// sweep.
+func resourceGithubTeamReadFromCache(d *schema.ResourceData, meta interface{}, cached *teamcache.Team) error {
+	d.Set("description", cached.Description)
+	d.Set("name", cached.Name)
+	d.Set("privacy", cached.Privacy)
+	if cached.HasParentTeam {
+		d.Set("parent_team_id", strconv.FormatInt(cached.ParentTeamDatabaseID, 10))
+	} else {
+		d.Set("parent_team_id", "")
+	}
+	d.Set("slug", cached.Slug)
+	d.Set("node_id", cached.NodeID)
+
+	if d.Get("manage_repositories").(bool) {
+		if err := readTeamRepositories(d, meta, cached.Slug); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("idp_groups").(*schema.Set).Len() > 0 {
+		client := meta.(*Owner).v3client
+		ctx := context.Background()
+		var idpGroups *github.IDPGroupList
+		err := retry.Do(ctx, teamRetryConfig(meta), func() error {
+			var rErr error
+			idpGroups, _, rErr = client.Teams.ListIDPGroupsForTeamBySlug(ctx, meta.(*Owner).name, cached.Slug)
+			return rErr
+		})
+		if err != nil {
+			return err
+		}
+		d.Set("idp_groups", flattenTeamSyncGroups(idpGroups.Groups))
+	}
+
+	return nil
+}
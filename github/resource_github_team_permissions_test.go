@@ -0,0 +1,178 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+// TestAccGithubTeam_permissions covers manage_repositories = true on
+// github_team: granting a repository, changing its permission level, and
+// removing it again, asserting the "permissions" set reflects each step.
+func TestAccGithubTeam_permissions(t *testing.T) {
+	rn := "github_team.test"
+	randString := acctest.RandStringFromCharSet(8, acctest.LowerCaseLetters)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGithubTeamDestroy,
+		Steps: []resource.TestStep{
+			{
+				// Add a repository grant.
+				Config: testAccGithubTeamPermissionsConfig(randString, "pull"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGithubTeamExists(rn, nil),
+					resource.TestCheckResourceAttr(rn, "permissions.#", "1"),
+					testAccCheckGithubTeamHasRepoPermission(rn, randString, "pull"),
+				),
+			},
+			{
+				// Change the permission on that repository.
+				Config: testAccGithubTeamPermissionsConfig(randString, "push"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGithubTeamExists(rn, nil),
+					resource.TestCheckResourceAttr(rn, "permissions.#", "1"),
+					testAccCheckGithubTeamHasRepoPermission(rn, randString, "push"),
+				),
+			},
+			{
+				// Remove the repository grant entirely.
+				Config: testAccGithubTeamPermissionsEmptyConfig(randString),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGithubTeamExists(rn, nil),
+					resource.TestCheckResourceAttr(rn, "permissions.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccGithubTeamPermissionsConfig(randString, permission string) string {
+	return fmt.Sprintf(`
+resource "github_repository" "test" {
+  name = "tf-acc-test-%[1]s"
+}
+
+resource "github_team" "test" {
+  name                 = "tf-acc-test-%[1]s"
+  manage_repositories  = true
+
+  permissions {
+    repository = github_repository.test.name
+    permission = %[2]q
+  }
+}
+`, randString, permission)
+}
+
+func testAccGithubTeamPermissionsEmptyConfig(randString string) string {
+	return fmt.Sprintf(`
+resource "github_repository" "test" {
+  name = "tf-acc-test-%[1]s"
+}
+
+resource "github_team" "test" {
+  name                = "tf-acc-test-%[1]s"
+  manage_repositories = true
+}
+`, randString)
+}
+
+// permissionsSetEntryRepositoryKey matches a "permissions" TypeSet entry's
+// "repository" attribute in flatmap state, e.g. "permissions.2541595766.repository".
+// TypeSet entries are keyed by content hash, not sequential index, so this
+// can't be walked the way a TypeList's "permissions.0", "permissions.1", ...
+// keys could be.
+var permissionsSetEntryRepositoryKey = regexp.MustCompile(`^permissions\.\d+\.repository$`)
+
+// testAccCheckGithubTeamHasRepoPermission asserts that one of the
+// "permissions" set entries in state grants repoNameSuffix's repository
+// exactly the given permission.
+func testAccCheckGithubTeamHasRepoPermission(rn, repoNameSuffix, permission string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		repoName := fmt.Sprintf("tf-acc-test-%s", repoNameSuffix)
+		for key, value := range rs.Primary.Attributes {
+			if !permissionsSetEntryRepositoryKey.MatchString(key) || value != repoName {
+				continue
+			}
+			permissionKey := strings.TrimSuffix(key, "repository") + "permission"
+			got := rs.Primary.Attributes[permissionKey]
+			if got != permission {
+				return fmt.Errorf("expected repository %s to have permission %q, got %q", repoName, permission, got)
+			}
+			return nil
+		}
+
+		return fmt.Errorf("repository %s not found in permissions set", repoName)
+	}
+}
+
+// testAccCheckGithubTeamExists asserts the github_team resource at rn
+// exists in state and, via the API, in GitHub. If team is non-nil the
+// fetched *github.Team is stored into it for further assertions.
+func testAccCheckGithubTeamExists(rn string, team *github.Team) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set for %s", rn)
+		}
+
+		id, err := strconv.ParseInt(rs.Primary.ID, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		conn := testAccProvider.Meta().(*Owner).v3client
+		got, _, err := conn.Teams.GetTeamByID(context.Background(), testAccProvider.Meta().(*Owner).id, id)
+		if err != nil {
+			return err
+		}
+
+		if team != nil {
+			*team = *got
+		}
+		return nil
+	}
+}
+
+func testAccCheckGithubTeamDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*Owner).v3client
+	orgID := testAccProvider.Meta().(*Owner).id
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "github_team" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(rs.Primary.ID, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		team, resp, err := conn.Teams.GetTeamByID(context.Background(), orgID, id)
+		if err == nil && team != nil {
+			return fmt.Errorf("team %s still exists", rs.Primary.ID)
+		}
+		if resp != nil && resp.StatusCode != 404 {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package github
+
+import (
+	"context"
+	"log"
+
+	"github.com/Flaconi/terraform-provider-github/internal/retry"
+	"github.com/google/go-github/v41/github"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// resourceGithubTeamSyncGroupMapping manages a GitHub Enterprise Cloud
+// Team Synchronization binding between a team and one or more SAML IdP
+// groups (https://docs.github.com/en/organizations/organizing-members-into-teams/synchronizing-a-team-with-an-identity-provider-group).
+// It's the SaaS-org equivalent of the "ldap_dn" field resourceGithubTeam
+// already offers GHES users.
+func resourceGithubTeamSyncGroupMapping() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGithubTeamSyncGroupMappingCreateOrUpdate,
+		Read:   resourceGithubTeamSyncGroupMappingRead,
+		Update: resourceGithubTeamSyncGroupMappingCreateOrUpdate,
+		Delete: resourceGithubTeamSyncGroupMappingDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"team_slug": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Slug of the team to bind to the IdP group(s).",
+			},
+			"group": {
+				Type:        schema.TypeList,
+				Required:    true,
+				Description: "SAML IdP groups synchronized with this team. See `github_organization_team_sync_groups` to look up IDs.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group_id": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"group_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"group_description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// expandTeamSyncGroups expands a "group"/"idp_groups"-shaped block's raw
+// list of entries into the IDPGroup slice the v3 API expects.
+// github_team_sync_group_mapping stores this as a TypeList under "group";
+// github_team stores the equivalent as a TypeSet under "idp_groups", so
+// callers read their own field into a []interface{} however their type
+// requires before calling this.
+func expandTeamSyncGroups(raw []interface{}) []*github.IDPGroup {
+	groups := make([]*github.IDPGroup, 0, len(raw))
+	for _, g := range raw {
+		group := g.(map[string]interface{})
+		groups = append(groups, &github.IDPGroup{
+			GroupID:          github.String(group["group_id"].(string)),
+			GroupName:        github.String(group["group_name"].(string)),
+			GroupDescription: github.String(group["group_description"].(string)),
+		})
+	}
+	return groups
+}
+
+func flattenTeamSyncGroups(groups []*github.IDPGroup) []interface{} {
+	flattened := make([]interface{}, 0, len(groups))
+	for _, group := range groups {
+		flattened = append(flattened, map[string]interface{}{
+			"group_id":          group.GetGroupID(),
+			"group_name":        group.GetGroupName(),
+			"group_description": group.GetGroupDescription(),
+		})
+	}
+	return flattened
+}
+
+func resourceGithubTeamSyncGroupMappingCreateOrUpdate(d *schema.ResourceData, meta interface{}) error {
+	err := checkOrganization(meta)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(*Owner).v3client
+	orgName := meta.(*Owner).name
+	teamSlug := d.Get("team_slug").(string)
+	ctx := context.Background()
+
+	log.Printf("[DEBUG] Creating/updating team sync group mapping for team: %s", teamSlug)
+	mapping := github.IDPGroupList{Groups: expandTeamSyncGroups(d.Get("group").([]interface{}))}
+	err = retry.Do(ctx, teamRetryConfig(meta), func() error {
+		_, _, rErr := client.Teams.CreateOrUpdateIDPGroupConnectionsBySlug(ctx, orgName, teamSlug, mapping)
+		return rErr
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(teamSlug)
+	return resourceGithubTeamSyncGroupMappingRead(d, meta)
+}
+
+func resourceGithubTeamSyncGroupMappingRead(d *schema.ResourceData, meta interface{}) error {
+	err := checkOrganization(meta)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(*Owner).v3client
+	orgName := meta.(*Owner).name
+	teamSlug := d.Id()
+	ctx := context.WithValue(context.Background(), ctxId, d.Id())
+
+	log.Printf("[DEBUG] Reading team sync group mapping for team: %s", teamSlug)
+	var idpGroups *github.IDPGroupList
+	err = retry.Do(ctx, teamRetryConfig(meta), func() error {
+		var rErr error
+		idpGroups, _, rErr = client.Teams.ListIDPGroupsForTeamBySlug(ctx, orgName, teamSlug)
+		return rErr
+	})
+	if err != nil {
+		if ghErr, ok := err.(*github.ErrorResponse); ok && ghErr.Response.StatusCode == 404 {
+			log.Printf("[WARN] Removing team sync group mapping %s from state because the team no longer exists in GitHub", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("team_slug", teamSlug)
+	d.Set("group", flattenTeamSyncGroups(idpGroups.Groups))
+
+	return nil
+}
+
+func resourceGithubTeamSyncGroupMappingDelete(d *schema.ResourceData, meta interface{}) error {
+	err := checkOrganization(meta)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(*Owner).v3client
+	orgName := meta.(*Owner).name
+	teamSlug := d.Id()
+	ctx := context.Background()
+
+	log.Printf("[DEBUG] Deleting team sync group mapping for team: %s", teamSlug)
+	return retry.Do(ctx, teamRetryConfig(meta), func() error {
+		_, _, rErr := client.Teams.CreateOrUpdateIDPGroupConnectionsBySlug(ctx, orgName, teamSlug, github.IDPGroupList{Groups: []*github.IDPGroup{}})
+		return rErr
+	})
+}
@@ -0,0 +1,95 @@
+package framework
+
+import (
+	"context"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+)
+
+// Provider is the terraform-plugin-framework side of the muxed provider
+// built by github.NewProviderServerFactory. It's only muxed in when
+// GITHUB_USE_LEGACY_TEAM_RESOURCE is false, in which case it takes over
+// github_team from the SDKv2 resourceGithubTeam.
+//
+// tf5muxserver forwards the same provider block configuration to every
+// server it mixes, so this provider configures its own v3/v4 clients from
+// that block rather than sharing the SDKv2 provider's *github.Owner.
+type Provider struct{}
+
+func New() provider.Provider {
+	return &Provider{}
+}
+
+type providerModel struct {
+	Token        types.String `tfsdk:"token"`
+	Organization types.String `tfsdk:"organization"`
+	BaseURL      types.String `tfsdk:"base_url"`
+}
+
+func (p *Provider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "github"
+}
+
+func (p *Provider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"token": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+			},
+			"organization": schema.StringAttribute{
+				Optional: true,
+			},
+			"base_url": schema.StringAttribute{
+				Optional: true,
+			},
+		},
+	}
+}
+
+func (p *Provider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config providerModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: config.Token.ValueString()},
+	))
+
+	v3client := github.NewClient(httpClient)
+	if baseURL := config.BaseURL.ValueString(); baseURL != "" {
+		enterpriseClient, err := github.NewEnterpriseClient(baseURL, baseURL, httpClient)
+		if err != nil {
+			resp.Diagnostics.AddError("Error configuring GitHub Enterprise client", err.Error())
+			return
+		}
+		v3client = enterpriseClient
+	}
+
+	data := &ProviderData{
+		Client:   v3client,
+		V4Client: githubv4.NewClient(httpClient),
+		Owner:    config.Organization.ValueString(),
+	}
+
+	resp.ResourceData = data
+}
+
+func (p *Provider) Resources(_ context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		NewTeamResource,
+	}
+}
+
+func (p *Provider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return nil
+}
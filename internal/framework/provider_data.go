@@ -0,0 +1,16 @@
+package framework
+
+import (
+	"github.com/google/go-github/v41/github"
+	"github.com/shurcooL/githubv4"
+)
+
+// ProviderData is handed to each framework resource's Configure method via
+// resource.ConfigureRequest.ProviderData. It mirrors the handful of fields
+// off the SDKv2 provider's *github.Owner that framework resources need,
+// rather than depending on the SDKv2 package directly.
+type ProviderData struct {
+	Client   *github.Client
+	V4Client *githubv4.Client
+	Owner    string
+}
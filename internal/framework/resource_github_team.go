@@ -0,0 +1,388 @@
+// Package framework hosts the terraform-plugin-framework resources that
+// are gradually replacing their terraform-plugin-sdk (v1) counterparts in
+// package github, following the same path PagerDuty used to migrate
+// pagerduty_team: a parallel framework implementation, muxed alongside the
+// SDK provider via tf5muxserver, so resources migrate one at a time instead
+// of all at once.
+package framework
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/githubv4"
+)
+
+// Compile-time interface checks, same convention the framework's own docs
+// use to catch schema/method mismatches early.
+var (
+	_ resource.Resource                   = &TeamResource{}
+	_ resource.ResourceWithConfigure      = &TeamResource{}
+	_ resource.ResourceWithImportState    = &TeamResource{}
+	_ resource.ResourceWithValidateConfig = &TeamResource{}
+)
+
+// TeamResource is the plugin-framework implementation of github_team. It's
+// registered behind the GITHUB_USE_LEGACY_TEAM_RESOURCE environment
+// variable main.go resolves before building the provider server: while
+// that's true (the default during transition) the SDKv2 resourceGithubTeam
+// handles github_team instead, and this resource isn't muxed in - see
+// github.NewProviderServerFactory.
+type TeamResource struct {
+	data *ProviderData
+}
+
+func NewTeamResource() resource.Resource {
+	return &TeamResource{}
+}
+
+type teamResourceModel struct {
+	ID                      types.String `tfsdk:"id"`
+	Name                    types.String `tfsdk:"name"`
+	Description             types.String `tfsdk:"description"`
+	Privacy                 types.String `tfsdk:"privacy"`
+	ParentTeamID            types.String `tfsdk:"parent_team_id"`
+	CreateDefaultMaintainer types.Bool   `tfsdk:"create_default_maintainer"`
+	Slug                    types.String `tfsdk:"slug"`
+	NodeID                  types.String `tfsdk:"node_id"`
+}
+
+func (r *TeamResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_team"
+}
+
+func (r *TeamResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a GitHub team. This is the terraform-plugin-framework implementation of github_team; set GITHUB_USE_LEGACY_TEAM_RESOURCE=true to opt back into the SDKv2 one during the migration window.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+			},
+			"privacy": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "One of \"secret\" or \"closed\". Defaults to \"secret\".",
+			},
+			"parent_team_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "ID or slug of the parent team. GitHub rejects `privacy = \"secret\"` when this is set: a secret team can't be nested.",
+			},
+			"create_default_maintainer": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+			},
+			"slug": schema.StringAttribute{
+				Computed: true,
+			},
+			"node_id": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (r *TeamResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected resource configure type",
+			"Expected *framework.ProviderData, got something else. Report this as a provider bug.",
+		)
+		return
+	}
+	r.data = data
+}
+
+// ValidateConfig rejects the combination GitHub itself rejects at apply
+// time (nested secret teams), so Terraform users see the problem during
+// `terraform plan` instead of a failed apply.
+func (r *TeamResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config teamResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.ParentTeamID.IsNull() || config.ParentTeamID.ValueString() == "" {
+		return
+	}
+
+	// Privacy is Optional+Computed and Create defaults an omitted value to
+	// "secret", so an unset privacy has to be judged as "secret" here too -
+	// otherwise the common case of just setting parent_team_id and leaving
+	// privacy unset would sail through plan-time validation and only fail
+	// once Create hits GitHub's API.
+	privacy := "secret"
+	if !config.Privacy.IsNull() && config.Privacy.ValueString() != "" {
+		privacy = config.Privacy.ValueString()
+	}
+	if privacy == "secret" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("privacy"),
+			"Invalid team configuration",
+			"GitHub does not allow a team with privacy = \"secret\" to have a parent_team_id set; use privacy = \"closed\" for nested teams.",
+		)
+	}
+}
+
+func (r *TeamResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan teamResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	privacy := "secret"
+	if !plan.Privacy.IsNull() && plan.Privacy.ValueString() != "" {
+		privacy = plan.Privacy.ValueString()
+	}
+
+	newTeam := github.NewTeam{
+		Name:        plan.Name.ValueString(),
+		Description: github.String(plan.Description.ValueString()),
+		Privacy:     github.String(privacy),
+	}
+
+	if !plan.ParentTeamID.IsNull() && plan.ParentTeamID.ValueString() != "" {
+		parentID := r.resolveTeamID(ctx, plan.ParentTeamID.ValueString(), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		newTeam.ParentTeamID = &parentID
+	}
+
+	team, _, err := r.data.Client.Teams.CreateTeam(ctx, r.data.Owner, newTeam)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating GitHub team", err.Error())
+		return
+	}
+
+	if plan.CreateDefaultMaintainer.IsNull() {
+		// GitHub doesn't report this back, and this schema sets no default;
+		// default it to false like the SDK resourceGithubTeam's schema does.
+		plan.CreateDefaultMaintainer = types.BoolValue(false)
+	}
+
+	if !plan.CreateDefaultMaintainer.ValueBool() {
+		r.removeDefaultMaintainer(ctx, team.GetSlug(), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	plan.ID = types.StringValue(strconv.FormatInt(team.GetID(), 10))
+	plan.Slug = types.StringValue(team.GetSlug())
+	plan.NodeID = types.StringValue(team.GetNodeID())
+	plan.Privacy = types.StringValue(team.GetPrivacy())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// removeDefaultMaintainer removes every current member (GitHub always adds
+// the creating user as a maintainer) from teamSlug, mirroring package
+// github's removeDefaultMaintainer for create_default_maintainer = false.
+func (r *TeamResource) removeDefaultMaintainer(ctx context.Context, teamSlug string, diags *diag.Diagnostics) {
+	type user struct {
+		Login githubv4.String
+	}
+	var query struct {
+		Organization struct {
+			Team struct {
+				Members struct {
+					Nodes []user
+				}
+			} `graphql:"team(slug:$slug)"`
+		} `graphql:"organization(login:$login)"`
+	}
+	variables := map[string]interface{}{
+		"slug":  githubv4.String(teamSlug),
+		"login": githubv4.String(r.data.Owner),
+	}
+
+	if err := r.data.V4Client.Query(ctx, &query, variables); err != nil {
+		diags.AddError("Error looking up default team maintainer", err.Error())
+		return
+	}
+
+	for _, member := range query.Organization.Team.Members.Nodes {
+		if _, err := r.data.Client.Teams.RemoveTeamMembershipBySlug(ctx, r.data.Owner, teamSlug, string(member.Login)); err != nil {
+			diags.AddError("Error removing default team maintainer", err.Error())
+			return
+		}
+	}
+}
+
+func (r *TeamResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state teamResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := strconv.ParseInt(state.ID.ValueString(), 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid team ID", err.Error())
+		return
+	}
+
+	team, httpResp, err := r.data.Client.Teams.GetTeamByID(ctx, r.ownerID(ctx, &resp.Diagnostics), id)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err != nil {
+		if httpResp != nil && httpResp.StatusCode == 404 {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading GitHub team", err.Error())
+		return
+	}
+
+	state.Name = types.StringValue(team.GetName())
+	state.Description = types.StringValue(team.GetDescription())
+	state.Privacy = types.StringValue(team.GetPrivacy())
+	state.Slug = types.StringValue(team.GetSlug())
+	state.NodeID = types.StringValue(team.GetNodeID())
+	if parent := team.Parent; parent != nil {
+		state.ParentTeamID = types.StringValue(strconv.FormatInt(parent.GetID(), 10))
+	} else {
+		state.ParentTeamID = types.StringNull()
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *TeamResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan teamResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := strconv.ParseInt(plan.ID.ValueString(), 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid team ID", err.Error())
+		return
+	}
+
+	editedTeam := github.NewTeam{
+		Name:        plan.Name.ValueString(),
+		Description: github.String(plan.Description.ValueString()),
+		Privacy:     github.String(plan.Privacy.ValueString()),
+	}
+
+	if !plan.ParentTeamID.IsNull() && plan.ParentTeamID.ValueString() != "" {
+		parentID := r.resolveTeamID(ctx, plan.ParentTeamID.ValueString(), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		editedTeam.ParentTeamID = &parentID
+	}
+
+	team, _, err := r.data.Client.Teams.EditTeamByID(ctx, r.ownerID(ctx, &resp.Diagnostics), id, editedTeam, false)
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating GitHub team", err.Error())
+		return
+	}
+
+	plan.Slug = types.StringValue(team.GetSlug())
+	plan.NodeID = types.StringValue(team.GetNodeID())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *TeamResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state teamResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := strconv.ParseInt(state.ID.ValueString(), 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid team ID", err.Error())
+		return
+	}
+
+	if _, err := r.data.Client.Teams.DeleteTeamByID(ctx, r.ownerID(ctx, &resp.Diagnostics), id); err != nil {
+		resp.Diagnostics.AddError("Error deleting GitHub team", err.Error())
+	}
+}
+
+// ImportState accepts either the numeric team ID github_team has always
+// used, or an "org/slug" pair for users who only know the team's slug.
+func (r *TeamResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importID := req.ID
+
+	if _, err := strconv.ParseInt(importID, 10, 64); err == nil {
+		resp.Diagnostics.Append(resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)...)
+		return
+	}
+
+	parts := strings.SplitN(importID, "/", 2)
+	if len(parts) != 2 {
+		resp.Diagnostics.AddError(
+			"Unexpected import identifier",
+			"Expected a numeric team ID or \"org/slug\", e.g. \"my-org/my-team\".",
+		)
+		return
+	}
+	orgName, slug := parts[0], parts[1]
+
+	team, _, err := r.data.Client.Teams.GetTeamBySlug(ctx, orgName, slug)
+	if err != nil {
+		resp.Diagnostics.AddError("Error resolving team slug during import", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), strconv.FormatInt(team.GetID(), 10))...)
+}
+
+// resolveTeamID resolves a parent_team_id value, which may itself be a
+// numeric ID or a slug, to the numeric ID GitHub's API wants.
+func (r *TeamResource) resolveTeamID(ctx context.Context, idOrSlug string, diags *diag.Diagnostics) int64 {
+	if id, err := strconv.ParseInt(idOrSlug, 10, 64); err == nil {
+		return id
+	}
+
+	team, _, err := r.data.Client.Teams.GetTeamBySlug(ctx, r.data.Owner, idOrSlug)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("parent_team_id"),
+			"Parent team not found",
+			"Could not resolve parent_team_id \""+idOrSlug+"\" to an existing team: "+err.Error(),
+		)
+		return 0
+	}
+	return team.GetID()
+}
+
+// ownerID resolves the configured organization's numeric ID. The framework
+// resource takes the organization login via ProviderData and looks up the
+// ID lazily, rather than caching it on the resource, to keep Configure
+// simple.
+func (r *TeamResource) ownerID(ctx context.Context, diags *diag.Diagnostics) int64 {
+	org, _, err := r.data.Client.Organizations.Get(ctx, r.data.Owner)
+	if err != nil {
+		diags.AddError("Error resolving organization", err.Error())
+		return 0
+	}
+	return org.GetID()
+}
@@ -0,0 +1,127 @@
+// Package retry centralizes the backoff/retry behaviour the provider needs
+// around the GitHub API. It replaces the hand-rolled `for i := 0; i < N; i++
+// { time.Sleep(...) }` loops that used to live inline in resources: those
+// loops retried blindly on any error and couldn't tell a transient 404 (team
+// not created yet on another parallel thread) from a secondary rate limit
+// or an outright permanent failure.
+package retry
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+// Config controls how an Operation is retried. Zero-value fields fall back
+// to DefaultConfig's values via WithDefaults.
+type Config struct {
+	// MaxAttempts is the maximum number of times an Operation is invoked
+	// before giving up, including the first attempt.
+	MaxAttempts int
+	// MaxWait is the upper bound on the total time spent retrying.
+	MaxWait time.Duration
+	// StatusCodes is the set of additional HTTP status codes (beyond the
+	// ones retry always treats as transient) that should be retried.
+	StatusCodes []int
+}
+
+// DefaultConfig mirrors the behaviour of the old hard-coded constants
+// (10 attempts, 5s apart) but expressed as a MaxWait-based exponential
+// backoff instead of a fixed sleep.
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts: 10,
+		MaxWait:     5 * time.Minute,
+		StatusCodes: []int{http.StatusNotFound},
+	}
+}
+
+// WithDefaults fills any zero-valued field of c with DefaultConfig's value.
+func (c Config) WithDefaults() Config {
+	d := DefaultConfig()
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = d.MaxAttempts
+	}
+	if c.MaxWait <= 0 {
+		c.MaxWait = d.MaxWait
+	}
+	if len(c.StatusCodes) == 0 {
+		c.StatusCodes = d.StatusCodes
+	}
+	return c
+}
+
+func (c Config) retryableStatus(code int) bool {
+	if code >= 500 {
+		return true
+	}
+	for _, sc := range c.StatusCodes {
+		if sc == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Operation is the unit of work retried by Do. It should return nil on
+// success, a *github.RateLimitError/*github.AbuseRateLimitError/
+// *github.ErrorResponse on an API failure, or any other error for failures
+// unrelated to the GitHub API (which are never retried).
+type Operation func() error
+
+// Do runs op, retrying it according to cfg whenever the returned error is
+// classified as transient: a primary rate limit (honouring
+// X-RateLimit-Reset), a secondary/abuse rate limit (honouring Retry-After),
+// or one of cfg's retryable HTTP status codes (404s and 5xxs by default).
+// Any other error is returned immediately as non-retryable.
+func Do(ctx context.Context, cfg Config, op Operation) error {
+	cfg = cfg.WithDefaults()
+	attempt := 0
+
+	return resource.RetryContext(ctx, cfg.MaxWait, func() *resource.RetryError {
+		attempt++
+		err := op()
+		if err == nil {
+			return nil
+		}
+
+		wait, retryable := classify(err, cfg)
+		if !retryable || attempt >= cfg.MaxAttempts {
+			return resource.NonRetryableError(err)
+		}
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+		return resource.RetryableError(err)
+	})
+}
+
+// classify inspects err and returns how long to wait before the next
+// attempt (0 meaning "let the backoff helper decide") and whether err is
+// transient at all.
+func classify(err error, cfg Config) (time.Duration, bool) {
+	switch e := err.(type) {
+	case *github.RateLimitError:
+		return time.Until(e.Rate.Reset.Time), true
+	case *github.AbuseRateLimitError:
+		if e.RetryAfter != nil {
+			return *e.RetryAfter, true
+		}
+		return 0, true
+	case *github.ErrorResponse:
+		if e.Response == nil {
+			return 0, false
+		}
+		if retryAfter := e.Response.Header.Get("Retry-After"); retryAfter != "" {
+			if d, parseErr := time.ParseDuration(retryAfter + "s"); parseErr == nil {
+				return d, true
+			}
+		}
+		return 0, cfg.retryableStatus(e.Response.StatusCode)
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,197 @@
+// Package teamcache coalesces team reads within a single "terraform apply"
+// window. Without it, resourceGithubTeamRead issues one REST GetTeamByID
+// per team and pays the retry.Do backoff penalty per resource on large
+// orgs, which is how the provider used to run into 404/secondary-rate-limit
+// churn in the first place. Cache fetches all of an organization's teams
+// in O(teams/100) GraphQL pages instead and serves reads out of that for
+// the configured TTL.
+package teamcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// Team is the subset of team fields the cache tracks, mirroring what
+// resourceGithubTeamRead needs out of a github.Team.
+type Team struct {
+	DatabaseID           int64
+	NodeID               string
+	Name                 string
+	Slug                 string
+	Description          string
+	Privacy              string
+	ParentTeamDatabaseID int64
+	HasParentTeam        bool
+}
+
+// Cache holds the most recent GraphQL sweep of an organization's teams,
+// indexed by both database ID and slug, and refreshes it at most once per
+// TTL.
+type Cache struct {
+	TTL time.Duration
+
+	mu        sync.Mutex
+	fetchedAt time.Time
+	byID      map[int64]*Team
+	bySlug    map[string]*Team
+	inflight  *refreshCall
+}
+
+// refreshCall represents one in-progress GraphQL sweep. Every caller that
+// joins it while it's running waits on done and then reads err, so a
+// failed sweep is reported to all of them, not just the caller that
+// happened to trigger it.
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// NewCache returns a Cache that refreshes its contents at most once every
+// ttl. A TTL of 0 disables caching; every Refresh call does a fresh sweep.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{TTL: ttl}
+}
+
+// GetByID returns the cached team for databaseID, if present and still
+// within the TTL.
+func (c *Cache) GetByID(databaseID int64) (*Team, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.freshLocked() {
+		return nil, false
+	}
+	team, ok := c.byID[databaseID]
+	return team, ok
+}
+
+// GetBySlug returns the cached team for slug, if present and still within
+// the TTL.
+func (c *Cache) GetBySlug(slug string) (*Team, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.freshLocked() {
+		return nil, false
+	}
+	team, ok := c.bySlug[slug]
+	return team, ok
+}
+
+func (c *Cache) freshLocked() bool {
+	return !c.fetchedAt.IsZero() && time.Since(c.fetchedAt) < c.TTL
+}
+
+// Refresh re-populates the cache from the v4 GraphQL API if it's stale.
+// Concurrent callers within the same stale window coalesce onto a single
+// GraphQL sweep instead of each issuing their own, and all of them
+// (not just the one that triggered the sweep) observe its actual result.
+func (c *Cache) Refresh(ctx context.Context, v4client *githubv4.Client, orgLogin string) error {
+	c.mu.Lock()
+	if c.freshLocked() {
+		c.mu.Unlock()
+		return nil
+	}
+	if call := c.inflight; call != nil {
+		c.mu.Unlock()
+		<-call.done
+		return call.err
+	}
+	call := &refreshCall{done: make(chan struct{})}
+	c.inflight = call
+	c.mu.Unlock()
+
+	err := c.fetch(ctx, v4client, orgLogin)
+
+	c.mu.Lock()
+	if err == nil {
+		c.fetchedAt = time.Now()
+	}
+	c.inflight = nil
+	c.mu.Unlock()
+
+	call.err = err
+	close(call.done)
+	return err
+}
+
+type teamSyncQuery struct {
+	Organization struct {
+		Teams struct {
+			Nodes []struct {
+				ID          githubv4.String
+				DatabaseID  githubv4.Int
+				Name        githubv4.String
+				Slug        githubv4.String
+				Description githubv4.String
+				Privacy     githubv4.String
+				ParentTeam  *struct {
+					DatabaseID githubv4.Int
+				}
+			}
+			PageInfo struct {
+				HasNextPage bool
+				EndCursor   githubv4.String
+			}
+		} `graphql:"teams(first: 100, after: $cursor)"`
+	} `graphql:"organization(login: $login)"`
+}
+
+// restPrivacy translates GraphQL's TeamPrivacy enum (SECRET/VISIBLE) to the
+// lowercase secret/closed strings the REST API - and the privacy
+// attribute's ValidateFunc - use everywhere else, so a cache-served read
+// doesn't write a value the user could never have configured into state.
+func restPrivacy(graphQLPrivacy string) string {
+	if graphQLPrivacy == "VISIBLE" {
+		return "closed"
+	}
+	return "secret"
+}
+
+func (c *Cache) fetch(ctx context.Context, v4client *githubv4.Client, orgLogin string) error {
+	byID := make(map[int64]*Team)
+	bySlug := make(map[string]*Team)
+
+	variables := map[string]interface{}{
+		"login":  githubv4.String(orgLogin),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	for {
+		var query teamSyncQuery
+		if err := v4client.Query(ctx, &query, variables); err != nil {
+			return err
+		}
+
+		for _, node := range query.Organization.Teams.Nodes {
+			team := &Team{
+				DatabaseID:  int64(node.DatabaseID),
+				NodeID:      string(node.ID),
+				Name:        string(node.Name),
+				Slug:        string(node.Slug),
+				Description: string(node.Description),
+				Privacy:     restPrivacy(string(node.Privacy)),
+			}
+			if node.ParentTeam != nil {
+				team.HasParentTeam = true
+				team.ParentTeamDatabaseID = int64(node.ParentTeam.DatabaseID)
+			}
+			byID[team.DatabaseID] = team
+			bySlug[team.Slug] = team
+		}
+
+		if !query.Organization.Teams.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = githubv4.NewString(query.Organization.Teams.PageInfo.EndCursor)
+	}
+
+	c.mu.Lock()
+	c.byID = byID
+	c.bySlug = bySlug
+	c.mu.Unlock()
+
+	return nil
+}